@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// swiftPackageInfoAspect is the aspect that writes a target's package info
+// to a JSON file, defined in //gazelle:swiftpackagesdriver.bzl.
+const swiftPackageInfoAspect = "//gazelle:swiftpackagesdriver.bzl%swift_package_info_aspect"
+
+// buildResultRe matches the "up-to-date" result block bazel build prints to
+// stderr for a single target, capturing its indented output paths, e.g.:
+//
+//	Target @repo//Sources/Foo:Foo up-to-date:
+//	  bazel-out/k8-fastbuild/bin/Sources/Foo/Foo.swiftpackageinfo.json
+var buildResultRe = regexp.MustCompile(`(?m)^Target .* up-to-date:\n((?:  .+\n?)*)`)
+
+// BazelJSONBuilder builds a Package by running swiftPackageInfoAspect over a
+// Bazel target and reading back the JSON file it writes, mirroring
+// rules_go's bazel_json aspect/builder for gopackagesdriver.
+type BazelJSONBuilder struct {
+	run bazelRunner
+}
+
+// NewBazelJSONBuilder creates a BazelJSONBuilder that runs bazel via run.
+func NewBazelJSONBuilder(run bazelRunner) *BazelJSONBuilder {
+	return &BazelJSONBuilder{run: run}
+}
+
+// Build runs swiftPackageInfoAspect over lbl, builds its output, and parses
+// the resulting JSON file into a Package.
+func (b *BazelJSONBuilder) Build(lbl string) (*Package, error) {
+	_, stderr, err := b.run("build", lbl,
+		"--aspects="+swiftPackageInfoAspect,
+		"--output_groups=swiftpackageinfo",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := outputGroupFile(stderr, lbl)
+	if err != nil {
+		return nil, err
+	}
+	return readPackageJSON(path)
+}
+
+// outputGroupFile extracts the first file bazel build reported it produced
+// for lbl's swiftpackageinfo output group from stderr. bazel build's
+// "up-to-date" result block is read instead of a follow-up
+// `cquery --output=files`, since --output=files lists a target's *default*
+// outputs and never includes files contributed by a non-default
+// --output_groups.
+func outputGroupFile(stderr []byte, lbl string) (string, error) {
+	m := buildResultRe.FindSubmatch(stderr)
+	if m == nil {
+		return "", fmt.Errorf("swift_package_info_aspect produced no output for %s", lbl)
+	}
+	paths := strings.Fields(string(m[1]))
+	if len(paths) == 0 {
+		return "", fmt.Errorf("swift_package_info_aspect produced no output for %s", lbl)
+	}
+	return paths[0], nil
+}
+
+// readPackageJSON reads and decodes the package info JSON file at path.
+func readPackageJSON(path string) (*Package, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading package info at %s: %w", path, err)
+	}
+	var p Package
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("decoding package info from %s: %w", path, err)
+	}
+	return &p, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBazelJSONBuilder_Build(t *testing.T) {
+	jsonPath := filepath.Join(t.TempDir(), "Foo.swiftpackageinfo.json")
+	err := os.WriteFile(jsonPath, []byte(`{"moduleName":"Foo","sources":["Sources/Foo/Foo.swift"],"swiftmodulePath":"Foo.swiftmodule","compileFlags":["-DFOO"],"deps":["@repo//Sources/Bar:Bar"]}`), 0o644)
+	assert.NoError(t, err)
+
+	var gotArgs [][]string
+	run := func(args ...string) ([]byte, []byte, error) {
+		gotArgs = append(gotArgs, args)
+		stderr := "Target @repo//Sources/Foo:Foo up-to-date:\n  " + jsonPath + "\n"
+		return nil, []byte(stderr), nil
+	}
+
+	pkg, err := NewBazelJSONBuilder(run).Build("@repo//Sources/Foo:Foo")
+	assert.NoError(t, err)
+	assert.Equal(t, &Package{
+		ModuleName:      "Foo",
+		Sources:         []string{"Sources/Foo/Foo.swift"},
+		SwiftmodulePath: "Foo.swiftmodule",
+		CompileFlags:    []string{"-DFOO"},
+		Deps:            []string{"@repo//Sources/Bar:Bar"},
+	}, pkg)
+
+	assert.Equal(t, []string{"build", "@repo//Sources/Foo:Foo", "--aspects=" + swiftPackageInfoAspect, "--output_groups=swiftpackageinfo"}, gotArgs[0])
+}
+
+// TestBazelJSONBuilder_BuildRealOutput guards against --output=files-style
+// regressions by feeding Build() a build result block shaped like bazel's
+// real stderr: a target line, an indented output-group file path, and the
+// trailing build-completed summary bazel always appends.
+func TestBazelJSONBuilder_BuildRealOutput(t *testing.T) {
+	jsonPath := filepath.Join(t.TempDir(), "Foo.swiftpackageinfo.json")
+	err := os.WriteFile(jsonPath, []byte(`{"moduleName":"Foo","sources":["Sources/Foo/Foo.swift"],"swiftmodulePath":"Foo.swiftmodule","compileFlags":[],"deps":[]}`), 0o644)
+	assert.NoError(t, err)
+
+	stderr := "INFO: Analyzed target @repo//Sources/Foo:Foo (0 packages loaded, 0 targets configured).\n" +
+		"INFO: Found 1 target...\n" +
+		"Target @repo//Sources/Foo:Foo up-to-date:\n" +
+		"  " + jsonPath + "\n" +
+		"INFO: Elapsed time: 0.123s, Critical Path: 0.01s\n" +
+		"INFO: Build completed successfully, 1 total action\n"
+
+	run := func(args ...string) ([]byte, []byte, error) {
+		return nil, []byte(stderr), nil
+	}
+
+	pkg, err := NewBazelJSONBuilder(run).Build("@repo//Sources/Foo:Foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "Foo", pkg.ModuleName)
+}
+
+func TestBazelJSONBuilder_BuildNoOutput(t *testing.T) {
+	run := func(args ...string) ([]byte, []byte, error) {
+		return nil, []byte("INFO: Build completed successfully, 0 total actions\n"), nil
+	}
+	_, err := NewBazelJSONBuilder(run).Build("@repo//Sources/Foo:Foo")
+	assert.Error(t, err)
+}
+
+func TestBazelJSONBuilder_BuildError(t *testing.T) {
+	run := func(args ...string) ([]byte, []byte, error) {
+		return nil, nil, fmt.Errorf("bazel exploded")
+	}
+	_, err := NewBazelJSONBuilder(run).Build("@repo//Sources/Foo:Foo")
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+// Command swiftpackagesdriver answers SourceKit-LSP / Xcode "resolve
+// module" queries by asking bazel for the owning target, mirroring what
+// rules_go's gopackagesdriver does for `go/packages`.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// bazelBinExternalRe matches absolute paths under a bazel output tree's
+// external repository directory, e.g.
+// "/home/user/repo/bazel-bin/external/some_repo/Sources/Foo/Foo.swift",
+// so they can be translated back into "@some_repo//Sources/Foo:Foo.swift".
+var bazelBinExternalRe = regexp.MustCompile(`bazel-[^/]+/external/([^/]+)/(.*)$`)
+
+// Query describes a single "resolve module" request, either a source file
+// path (as SourceKit-LSP sends) or a bare Bazel label / import path (as
+// `swift build --print-manifest`-style CLI tooling sends).
+type Query struct {
+	// File is an absolute path to a Swift source file.
+	File string
+	// Label is a bare Bazel label or import path.
+	Label string
+}
+
+// ParseQuery parses a single driver argument of the form "file=<path>" or a
+// bare label/import-path string.
+func ParseQuery(arg string) Query {
+	if path, ok := strings.CutPrefix(arg, "file="); ok {
+		return Query{File: path}
+	}
+	return Query{Label: arg}
+}
+
+// LabelForPath translates an absolute path under a bazel output tree's
+// external repository directory (bazel-*/external/<repo>/...) into the
+// Bazel label @<repo>//<pkg>:<file> for that source file.
+func LabelForPath(path string) (string, bool) {
+	m := bazelBinExternalRe.FindStringSubmatch(path)
+	if m == nil {
+		return "", false
+	}
+	repo, rest := m[1], m[2]
+	pkg, file := rest, rest
+	if idx := strings.LastIndex(rest, "/"); idx != -1 {
+		pkg, file = rest[:idx], rest[idx+1:]
+	}
+	return fmt.Sprintf("@%s//%s:%s", repo, pkg, file), true
+}
+
+// isBazelLabel reports whether s looks like a Bazel label rather than a
+// bare Swift import path (the string `swift build --print-manifest`-style
+// CLI tooling sends). Labels always carry a "//" package separator, a
+// leading ":" relative-target shorthand, or a "@repo" qualifier; a plain
+// import path like "Foo" has none of those.
+func isBazelLabel(s string) bool {
+	return strings.Contains(s, "//") || strings.HasPrefix(s, ":") || strings.HasPrefix(s, "@")
+}
+
+// Package describes the module a query resolved to, in the shape Xcode's
+// SourceKit-LSP expects for workspace/didChangeConfiguration.
+type Package struct {
+	ModuleName      string   `json:"moduleName"`
+	Sources         []string `json:"sources"`
+	SwiftmodulePath string   `json:"swiftmodulePath"`
+	CompileFlags    []string `json:"compileFlags"`
+	Deps            []string `json:"deps"`
+}
+
+// bazelRunner runs a bazel subcommand and returns its stdout and stderr.
+type bazelRunner func(args ...string) (stdout, stderr []byte, err error)
+
+func runBazel(args ...string) ([]byte, []byte, error) {
+	cmd := exec.Command("bazel", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, nil, fmt.Errorf("bazel %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), stderr.Bytes(), nil
+}
+
+// owningLabel finds the swift_library/swift_test/swift_binary target that
+// owns lbl by querying for its same-package direct reverse dependents.
+func owningLabel(run bazelRunner, lbl string) (string, error) {
+	expr := fmt.Sprintf(`kind("swift_library|swift_test|swift_binary", same_pkg_direct_rdeps(%q))`, lbl)
+	out, _, err := run("cquery", expr, "--output=label")
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Fields(strings.TrimSpace(string(out)))
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no owning target found for %s", lbl)
+	}
+	sort.Strings(lines)
+	return lines[0], nil
+}
+
+// labelForImportPath finds the swift_library/swift_test/swift_binary
+// target whose module_name matches importPath, the label counterpart of a
+// bare import-path query. Unlike owningLabel, the target this returns is
+// already the target the driver should describe - there's no intervening
+// source file to resolve same-package reverse dependents from.
+func labelForImportPath(run bazelRunner, importPath string) (string, error) {
+	expr := fmt.Sprintf(`attr(module_name, "^%s$", kind("swift_library|swift_test|swift_binary", //...))`, regexp.QuoteMeta(importPath))
+	out, _, err := run("query", expr, "--output=label")
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Fields(strings.TrimSpace(string(out)))
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no target found for import path %s", importPath)
+	}
+	sort.Strings(lines)
+	return lines[0], nil
+}
+
+// Driver answers Queries by finding their owning Bazel target, building
+// that target's package info, and de-duplicating the result against every
+// other package resolved in the same driver invocation.
+type Driver struct {
+	run      bazelRunner
+	builder  *BazelJSONBuilder
+	registry *Registry
+}
+
+// NewDriver creates a Driver that runs bazel via run.
+func NewDriver(run bazelRunner) *Driver {
+	return &Driver{
+		run:      run,
+		builder:  NewBazelJSONBuilder(run),
+		registry: NewRegistry(),
+	}
+}
+
+// Resolve answers q by finding its owning Bazel target and returning the
+// package info for that target, merged with any other target already
+// resolved to the same module name.
+func (d *Driver) Resolve(q Query) (*Package, error) {
+	owner, err := d.owner(q)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := d.builder.Build(owner)
+	if err != nil {
+		return nil, err
+	}
+	return d.registry.Add(pkg), nil
+}
+
+// owner resolves q to the label of the target the driver should describe.
+func (d *Driver) owner(q Query) (string, error) {
+	if q.File != "" {
+		lbl, ok := LabelForPath(q.File)
+		if !ok {
+			return "", fmt.Errorf("could not translate %s to a bazel label", q.File)
+		}
+		return owningLabel(d.run, lbl)
+	}
+	if isBazelLabel(q.Label) {
+		return owningLabel(d.run, q.Label)
+	}
+	return labelForImportPath(d.run, q.Label)
+}
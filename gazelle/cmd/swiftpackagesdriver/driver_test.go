@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      string
+		expected Query
+	}{
+		{
+			name:     "file query",
+			arg:      "file=/abs/path/Foo.swift",
+			expected: Query{File: "/abs/path/Foo.swift"},
+		},
+		{
+			name:     "bare label query",
+			arg:      "@repo//Sources/Foo:Foo",
+			expected: Query{Label: "@repo//Sources/Foo:Foo"},
+		},
+		{
+			name:     "import path query",
+			arg:      "Foo",
+			expected: Query{Label: "Foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseQuery(tt.arg))
+		})
+	}
+}
+
+func TestLabelForPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		expected   string
+		expectedOk bool
+	}{
+		{
+			name:       "external repo file",
+			path:       "/home/user/repo/bazel-bin/external/some_repo/Sources/Foo/Foo.swift",
+			expected:   "@some_repo//Sources/Foo:Foo.swift",
+			expectedOk: true,
+		},
+		{
+			name:       "non-bazel path",
+			path:       "/home/user/repo/Sources/Foo/Foo.swift",
+			expected:   "",
+			expectedOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := LabelForPath(tt.path)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDriverResolve(t *testing.T) {
+	jsonPath := filepath.Join(t.TempDir(), "Foo.swiftpackageinfo.json")
+	err := os.WriteFile(jsonPath, []byte(`{"moduleName":"Foo","sources":["Sources/Foo/Foo.swift"],"swiftmodulePath":"Foo.swiftmodule","compileFlags":[],"deps":[]}`), 0o644)
+	assert.NoError(t, err)
+
+	var gotArgs [][]string
+	run := func(args ...string) ([]byte, []byte, error) {
+		gotArgs = append(gotArgs, args)
+		switch args[0] {
+		case "cquery":
+			if args[1] == `kind("swift_library|swift_test|swift_binary", same_pkg_direct_rdeps("@repo//Sources/Foo:Foo.swift"))` {
+				return []byte("@repo//Sources/Foo:Foo\n"), nil, nil
+			}
+		case "build":
+			return nil, []byte("Target @repo//Sources/Foo:Foo up-to-date:\n  " + jsonPath + "\n"), nil
+		}
+		return nil, nil, nil
+	}
+
+	pkg, err := NewDriver(run).Resolve(Query{File: "/repo/bazel-bin/external/repo/Sources/Foo/Foo.swift"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Foo", pkg.ModuleName)
+
+	assert.Equal(t, []string{"build", "@repo//Sources/Foo:Foo", "--aspects=" + swiftPackageInfoAspect, "--output_groups=swiftpackageinfo"}, gotArgs[1])
+}
+
+func TestDriverResolveUntranslatableFile(t *testing.T) {
+	run := func(args ...string) ([]byte, []byte, error) {
+		return nil, nil, fmt.Errorf("bazel should not be invoked")
+	}
+	_, err := NewDriver(run).Resolve(Query{File: "/not/a/bazel/path/Foo.swift"})
+	assert.Error(t, err)
+}
+
+func TestDriverResolveImportPath(t *testing.T) {
+	jsonPath := filepath.Join(t.TempDir(), "Foo.swiftpackageinfo.json")
+	err := os.WriteFile(jsonPath, []byte(`{"moduleName":"Foo","sources":["Sources/Foo/Foo.swift"],"swiftmodulePath":"Foo.swiftmodule","compileFlags":[],"deps":[]}`), 0o644)
+	assert.NoError(t, err)
+
+	var gotArgs [][]string
+	run := func(args ...string) ([]byte, []byte, error) {
+		gotArgs = append(gotArgs, args)
+		switch args[0] {
+		case "query":
+			if args[1] == `attr(module_name, "^Foo$", kind("swift_library|swift_test|swift_binary", //...))` {
+				return []byte("@repo//Sources/Foo:Foo\n"), nil, nil
+			}
+		case "build":
+			return nil, []byte("Target @repo//Sources/Foo:Foo up-to-date:\n  " + jsonPath + "\n"), nil
+		}
+		return nil, nil, nil
+	}
+
+	pkg, err := NewDriver(run).Resolve(Query{Label: "Foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Foo", pkg.ModuleName)
+
+	assert.Equal(t, []string{"query", `attr(module_name, "^Foo$", kind("swift_library|swift_test|swift_binary", //...))`, "--output=label"}, gotArgs[0])
+}
+
+func TestDriverResolveImportPathNotFound(t *testing.T) {
+	run := func(args ...string) ([]byte, []byte, error) {
+		return []byte(""), nil, nil
+	}
+	_, err := NewDriver(run).Resolve(Query{Label: "Foo"})
+	assert.Error(t, err)
+}
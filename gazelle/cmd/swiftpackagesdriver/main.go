@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := runInto(os.Stdout, os.Args[1:], runBazel); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runInto resolves each of args against bazelRun and writes the distinct
+// resolved Packages to w as JSON. Two args that resolve to the same module
+// (e.g. a library and its test target, or two file= queries in the same
+// module) contribute a single entry, since driver.Resolve returns the
+// Registry's canonical *Package for repeat module names.
+func runInto(w io.Writer, args []string, bazelRun bazelRunner) error {
+	driver := NewDriver(bazelRun)
+	seen := make(map[*Package]bool, len(args))
+	pkgs := make([]*Package, 0, len(args))
+	for _, arg := range args {
+		pkg, err := driver.Resolve(ParseQuery(arg))
+		if err != nil {
+			return err
+		}
+		if seen[pkg] {
+			continue
+		}
+		seen[pkg] = true
+		pkgs = append(pkgs, pkg)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(pkgs)
+}
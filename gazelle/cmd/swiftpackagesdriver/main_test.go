@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunDedupesSharedModule exercises the case registry.go documents: two
+// queries that resolve to distinct Bazel targets sharing a module name
+// (e.g. a library and its test target) must surface that module only once
+// in the JSON emitted to Xcode, not once per query argument.
+func TestRunDedupesSharedModule(t *testing.T) {
+	dir := t.TempDir()
+	libJSON := filepath.Join(dir, "Foo.swiftpackageinfo.json")
+	testJSON := filepath.Join(dir, "FooTests.swiftpackageinfo.json")
+	assert.NoError(t, os.WriteFile(libJSON, []byte(`{"moduleName":"Foo","sources":["Sources/Foo/Foo.swift"],"swiftmodulePath":"Foo.swiftmodule","compileFlags":[],"deps":[]}`), 0o644))
+	assert.NoError(t, os.WriteFile(testJSON, []byte(`{"moduleName":"Foo","sources":["Tests/FooTests/FooTests.swift"],"swiftmodulePath":"Foo.swiftmodule","compileFlags":[],"deps":[]}`), 0o644))
+
+	bazelRun := func(args ...string) ([]byte, []byte, error) {
+		switch {
+		case args[0] == "cquery" && len(args) > 1 && args[1] == `kind("swift_library|swift_test|swift_binary", same_pkg_direct_rdeps("@repo//Sources/Foo:Foo"))`:
+			return []byte("@repo//Sources/Foo:Foo\n"), nil, nil
+		case args[0] == "cquery" && len(args) > 1 && args[1] == `kind("swift_library|swift_test|swift_binary", same_pkg_direct_rdeps("@repo//Tests/FooTests:FooTests"))`:
+			return []byte("@repo//Tests/FooTests:FooTests\n"), nil, nil
+		case args[0] == "build" && args[1] == "@repo//Sources/Foo:Foo":
+			return nil, []byte("Target @repo//Sources/Foo:Foo up-to-date:\n  " + libJSON + "\n"), nil
+		case args[0] == "build" && args[1] == "@repo//Tests/FooTests:FooTests":
+			return nil, []byte("Target @repo//Tests/FooTests:FooTests up-to-date:\n  " + testJSON + "\n"), nil
+		}
+		return nil, nil, nil
+	}
+
+	var out bytes.Buffer
+	err := runInto(&out, []string{"@repo//Sources/Foo:Foo", "@repo//Tests/FooTests:FooTests"}, bazelRun)
+	assert.NoError(t, err)
+
+	var pkgs []*Package
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &pkgs))
+	assert.Len(t, pkgs, 1)
+	assert.Equal(t, "Foo", pkgs[0].ModuleName)
+}
@@ -0,0 +1,45 @@
+package main
+
+// Registry de-duplicates Packages by module name. SPM routinely produces
+// more than one Bazel target (e.g. a library and the test target that
+// re-compiles its sources) sharing a single module name, and Xcode only
+// wants to hear about that module once.
+type Registry struct {
+	byModule map[string]*Package
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{byModule: map[string]*Package{}}
+}
+
+// Add registers pkg under its module name and returns the canonical Package
+// for that module: the first Package seen for the module name, with pkg's
+// sources, deps, and compile flags merged in.
+func (r *Registry) Add(pkg *Package) *Package {
+	existing, ok := r.byModule[pkg.ModuleName]
+	if !ok {
+		r.byModule[pkg.ModuleName] = pkg
+		return pkg
+	}
+	existing.Sources = mergeUnique(existing.Sources, pkg.Sources)
+	existing.Deps = mergeUnique(existing.Deps, pkg.Deps)
+	existing.CompileFlags = mergeUnique(existing.CompileFlags, pkg.CompileFlags)
+	return existing
+}
+
+// mergeUnique concatenates a and b, dropping duplicates and preserving
+// first-seen order.
+func mergeUnique(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, s := range list {
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
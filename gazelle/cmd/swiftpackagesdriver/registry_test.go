@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_AddDistinctModules(t *testing.T) {
+	r := NewRegistry()
+	foo := &Package{ModuleName: "Foo", Sources: []string{"Foo.swift"}}
+	bar := &Package{ModuleName: "Bar", Sources: []string{"Bar.swift"}}
+
+	assert.Same(t, foo, r.Add(foo))
+	assert.Same(t, bar, r.Add(bar))
+}
+
+func TestRegistry_AddSameModuleDifferentLabels(t *testing.T) {
+	r := NewRegistry()
+	lib := &Package{
+		ModuleName: "Foo",
+		Sources:    []string{"Sources/Foo/Foo.swift"},
+		Deps:       []string{"@repo//Sources/Bar:Bar"},
+	}
+	test := &Package{
+		ModuleName: "Foo",
+		Sources:    []string{"Sources/Foo/Foo.swift", "Tests/FooTests/FooTests.swift"},
+		Deps:       []string{"@repo//Sources/Baz:Baz"},
+	}
+
+	canonical := r.Add(lib)
+	assert.Same(t, lib, canonical)
+
+	merged := r.Add(test)
+	assert.Same(t, lib, merged)
+	assert.Equal(t, []string{"Sources/Foo/Foo.swift", "Tests/FooTests/FooTests.swift"}, merged.Sources)
+	assert.Equal(t, []string{"@repo//Sources/Bar:Bar", "@repo//Sources/Baz:Baz"}, merged.Deps)
+}
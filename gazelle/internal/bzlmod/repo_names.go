@@ -0,0 +1,163 @@
+// Package bzlmod resolves the apparent-repo-name to canonical-repo-name
+// mapping for Swift dependencies declared via Bzlmod, so that
+// swift.BazelLabelFromTarget can emit labels against the right repository.
+package bzlmod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bazelbuild/buildtools/build"
+)
+
+// CanonicalRepoName returns the canonical repo name that names maps
+// apparentName to, falling back to apparentName unchanged when it isn't
+// declared through the swift_deps extension (e.g. a WORKSPACE-era repo
+// name, or a Bzlmod workspace with no matching entry).
+func CanonicalRepoName(names map[string]string, apparentName string) string {
+	if canonical, ok := names[apparentName]; ok {
+		return canonical
+	}
+	return apparentName
+}
+
+// swiftDepsExtension is the name of the module extension that Bzlmod
+// workspaces use to declare Swift package dependencies, analogous to the
+// swift_deps.bzl macro used under WORKSPACE.
+const swiftDepsExtension = "swift_deps"
+
+// RepoNameMap walks the MODULE.bazel file at rootPath - following every
+// include() directive it contains, recursively - and returns the flattened
+// apparent-repo-name -> canonical-repo-name map for repos declared through
+// the swift_deps module extension, honoring use_repo renames along the way.
+func RepoNameMap(rootPath string) (map[string]string, error) {
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	stmts, err := loadStatements(filepath.Dir(absRoot), absRoot, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	proxies := map[string]bool{}
+	names := map[string]string{}
+	for _, stmt := range stmts {
+		if assign, ok := stmt.(*build.AssignExpr); ok {
+			if call, ok := assign.RHS.(*build.CallExpr); ok && callName(call) == "use_extension" {
+				if ident, ok := assign.LHS.(*build.Ident); ok && extensionArg(call) == swiftDepsExtension {
+					proxies[ident.Name] = true
+				}
+			}
+			continue
+		}
+
+		call, ok := stmt.(*build.CallExpr)
+		if !ok || callName(call) != "use_repo" || len(call.List) == 0 {
+			continue
+		}
+		proxy, ok := call.List[0].(*build.Ident)
+		if !ok || !proxies[proxy.Name] {
+			continue
+		}
+		addUseRepoNames(names, call.List[1:])
+	}
+	return names, nil
+}
+
+// loadStatements parses the MODULE.bazel-style file at path and returns its
+// top-level statements, with every include(...) call replaced by the
+// statements of the included file (recursively, depth-first). include()
+// labels are resolved relative to repoRoot, matching how Bazel resolves
+// "//:path/to/file" labels against the repository root regardless of which
+// file the include() call appears in.
+func loadStatements(repoRoot, path string, visited map[string]bool) ([]build.Expr, error) {
+	if visited[path] {
+		return nil, nil
+	}
+	visited[path] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	f, err := build.ParseModule(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	var stmts []build.Expr
+	for _, stmt := range f.Stmt {
+		call, ok := stmt.(*build.CallExpr)
+		if !ok || callName(call) != "include" {
+			stmts = append(stmts, stmt)
+			continue
+		}
+		label, ok := includeArg(call)
+		if !ok {
+			continue
+		}
+		included, err := loadStatements(repoRoot, filepath.Join(repoRoot, strings.TrimPrefix(label, "//:")), visited)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, included...)
+	}
+	return stmts, nil
+}
+
+// addUseRepoNames records the apparent->canonical mapping for each use_repo
+// argument: a bare string "foo" means apparent name "foo" maps to the
+// extension-generated repo "foo", while a kwarg "bar = \"foo\"" renames the
+// apparent name to "bar" for the same canonical repo "foo".
+func addUseRepoNames(names map[string]string, args []build.Expr) {
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case *build.StringExpr:
+			names[a.Value] = a.Value
+		case *build.AssignExpr:
+			lhs, ok := a.LHS.(*build.Ident)
+			rhs, ok2 := a.RHS.(*build.StringExpr)
+			if ok && ok2 {
+				names[lhs.Name] = rhs.Value
+			}
+		}
+	}
+}
+
+// callName returns the function name of a call expression, e.g. "include"
+// for include("//:foo.MODULE.bazel").
+func callName(call *build.CallExpr) string {
+	ident, ok := call.X.(*build.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name
+}
+
+// includeArg returns the single string argument passed to an include() call.
+func includeArg(call *build.CallExpr) (string, bool) {
+	if len(call.List) != 1 {
+		return "", false
+	}
+	s, ok := call.List[0].(*build.StringExpr)
+	if !ok {
+		return "", false
+	}
+	return s.Value, true
+}
+
+// extensionArg returns the extension name passed to a use_extension() call,
+// e.g. "swift_deps" for use_extension("@swift_bazel//:extensions.bzl", "swift_deps").
+func extensionArg(call *build.CallExpr) string {
+	if len(call.List) < 2 {
+		return ""
+	}
+	s, ok := call.List[1].(*build.StringExpr)
+	if !ok {
+		return ""
+	}
+	return s.Value
+}
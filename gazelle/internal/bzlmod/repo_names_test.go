@@ -0,0 +1,24 @@
+package bzlmod
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoNameMap(t *testing.T) {
+	names, err := RepoNameMap("testdata/root/MODULE.bazel")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"swift_argument_parser": "swift_argument_parser",
+		"swift_log":             "swift_log",
+		"nested_snapshot":       "swift_snapshot_testing",
+	}, names)
+}
+
+func TestCanonicalRepoName(t *testing.T) {
+	names := map[string]string{"nested_snapshot": "swift_snapshot_testing"}
+
+	assert.Equal(t, "swift_snapshot_testing", CanonicalRepoName(names, "nested_snapshot"))
+	assert.Equal(t, "swift_log", CanonicalRepoName(names, "swift_log"))
+}
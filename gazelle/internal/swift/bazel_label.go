@@ -5,19 +5,85 @@ import (
 	"strings"
 
 	"github.com/bazelbuild/bazel-gazelle/label"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+	"github.com/cgrindel/swift_bazel/gazelle/internal/bzlmod"
 	"github.com/cgrindel/swift_bazel/gazelle/internal/swiftpkg"
 )
 
-// BazelLabelFromTarget creates a Bazel label from a Swift target.
+// BazelLabelFromTarget creates a Bazel label from a Swift target, going
+// through the same TargetBuilder.Label a caller that already has a builder
+// in hand would use, so there is exactly one place that derives a Bazel
+// label name from a (pkg, name) pair.
 func BazelLabelFromTarget(repoName string, target *swiftpkg.Target) *label.Label {
-	var name string
-	basename := path.Base(target.Path)
-	if basename == target.Name {
-		name = target.Path
+	return NewTargetBuilder("", target.Name, target.Path).Label(repoName)
+}
+
+// BazelLabelFromTargetWithRepoNames is BazelLabelFromTarget, but resolves
+// apparentRepoName through repoNames first. In a Bzlmod workspace, the repo
+// name a swift_deps dependency was declared under (its apparent name) can
+// differ from the canonical repo name Bazel actually resolves labels
+// against, so repoNames - built by bzlmod.RepoNameMap - must be consulted
+// before the label is synthesized.
+func BazelLabelFromTargetWithRepoNames(repoNames map[string]string, apparentRepoName string, target *swiftpkg.Target) *label.Label {
+	return BazelLabelFromTarget(bzlmod.CanonicalRepoName(repoNames, apparentRepoName), target)
+}
+
+// bazelLabelName synthesizes the Bazel target name for a Swift target at
+// pkgPath with the given name. Keep this in sync with
+// swiftpkg/internal/pkginfo_targets.bzl's bazel_label_name_from_parts.
+//
+// The "path is the same as the name" shortcut is only safe when the target
+// lives in a subdirectory. A root-level target (e.g. a target named "Foo" at
+// path "Foo") would otherwise collapse to the name "Foo", which collides
+// with a product of the same name. In that case, fall back to joining the
+// path and name like any other target.
+func bazelLabelName(pkgPath, name string) string {
+	var result string
+	if path.Base(pkgPath) == name && path.Dir(pkgPath) != "." {
+		result = pkgPath
 	} else {
-		name = path.Join(target.Path, target.Name)
+		result = path.Join(pkgPath, name)
+	}
+	return strings.ReplaceAll(result, "/", "_")
+}
+
+// FilterPhantomTargets removes "phantom" targets from dumpTargets: targets
+// that appear in SPM's `swift package describe` output but that the
+// package's manifest never actually declares. Gazelle must not synthesize
+// Bazel labels for these, or it will emit targets that don't exist.
+func FilterPhantomTargets(dumpTargets []*swiftpkg.Target, declaredNames map[string]bool) []*swiftpkg.Target {
+	real := make([]*swiftpkg.Target, 0, len(dumpTargets))
+	for _, target := range dumpTargets {
+		if declaredNames[target.Name] {
+			real = append(real, target)
+		}
+	}
+	return real
+}
+
+// BazelLabelsFromTargets creates Bazel labels for all of the non-phantom
+// targets in dumpTargets, filtering against declaredNames first so that
+// gazelle never emits a dangling Bazel target.
+func BazelLabelsFromTargets(repoName string, dumpTargets []*swiftpkg.Target, declaredNames map[string]bool) []*label.Label {
+	real := FilterPhantomTargets(dumpTargets, declaredNames)
+	lbls := make([]*label.Label, 0, len(real))
+	for _, target := range real {
+		lbls = append(lbls, BazelLabelFromTarget(repoName, target))
+	}
+	return lbls
+}
+
+// RulesFromTargets builds the *rule.Rule skeleton for every non-phantom
+// target in dumpTargets, filtering against declaredNames the same way
+// BazelLabelsFromTargets does. Each rule is built through a TargetBuilder
+// seeded from the target's name and path, the same starting point a
+// generator fills in with srcs, deps, and the rest before emitting the
+// rule to a BUILD file.
+func RulesFromTargets(kind string, dumpTargets []*swiftpkg.Target, declaredNames map[string]bool) []*rule.Rule {
+	real := FilterPhantomTargets(dumpTargets, declaredNames)
+	rules := make([]*rule.Rule, 0, len(real))
+	for _, target := range real {
+		rules = append(rules, NewTargetBuilder(kind, target.Name, target.Path).Build())
 	}
-	name = strings.ReplaceAll(name, "/", "_")
-	lbl := label.New(repoName, "", name)
-	return &lbl
+	return rules
 }
@@ -0,0 +1,86 @@
+package swift
+
+import (
+	"testing"
+
+	"github.com/bazelbuild/bazel-gazelle/label"
+	"github.com/cgrindel/swift_bazel/gazelle/internal/bzlmod"
+	"github.com/cgrindel/swift_bazel/gazelle/internal/swiftpkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBazelLabelFromTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoName string
+		target   *swiftpkg.Target
+		expected label.Label
+	}{
+		{
+			name:     "nested target with matching name",
+			repoName: "",
+			target:   &swiftpkg.Target{Name: "Foo", Path: "Sources/Foo"},
+			expected: label.New("", "", "Sources_Foo"),
+		},
+		{
+			name:     "root-level target with matching name",
+			repoName: "",
+			target:   &swiftpkg.Target{Name: "Foo", Path: "Foo"},
+			expected: label.New("", "", "Foo_Foo"),
+		},
+		{
+			name:     "nested target with differing name",
+			repoName: "",
+			target:   &swiftpkg.Target{Name: "FooTests", Path: "Tests/Foo"},
+			expected: label.New("", "", "Tests_Foo_FooTests"),
+		},
+		{
+			name:     "name/product collision at the root",
+			repoName: "",
+			target:   &swiftpkg.Target{Name: "Product", Path: "Product"},
+			expected: label.New("", "", "Product_Product"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := BazelLabelFromTarget(tt.repoName, tt.target)
+			assert.Equal(t, tt.expected, *actual)
+		})
+	}
+}
+
+func TestBazelLabelFromTargetWithRepoNames(t *testing.T) {
+	repoNames, err := bzlmod.RepoNameMap("../bzlmod/testdata/root/MODULE.bazel")
+	assert.NoError(t, err)
+
+	target := &swiftpkg.Target{Name: "SnapshotTesting", Path: "Sources/SnapshotTesting"}
+
+	// "nested_snapshot" is declared by a use_repo() in a MODULE.bazel
+	// segment reached through a nested include(), and is renamed from the
+	// canonical repo "swift_snapshot_testing".
+	actual := BazelLabelFromTargetWithRepoNames(repoNames, "nested_snapshot", target)
+	expected := label.New("swift_snapshot_testing", "", "Sources_SnapshotTesting")
+	assert.Equal(t, expected, *actual)
+}
+
+func TestFilterPhantomTargets(t *testing.T) {
+	foo := &swiftpkg.Target{Name: "Foo", Path: "Sources/Foo"}
+	phantom := &swiftpkg.Target{Name: "Phantom", Path: "Sources/Phantom"}
+
+	declaredNames := map[string]bool{"Foo": true}
+	actual := FilterPhantomTargets([]*swiftpkg.Target{foo, phantom}, declaredNames)
+
+	assert.Equal(t, []*swiftpkg.Target{foo}, actual)
+}
+
+func TestRulesFromTargets(t *testing.T) {
+	foo := &swiftpkg.Target{Name: "Foo", Path: "Sources/Foo"}
+	phantom := &swiftpkg.Target{Name: "Phantom", Path: "Sources/Phantom"}
+
+	declaredNames := map[string]bool{"Foo": true}
+	rules := RulesFromTargets("swift_library", []*swiftpkg.Target{foo, phantom}, declaredNames)
+
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "swift_library", rules[0].Kind())
+	assert.Equal(t, "Foo", rules[0].Name())
+}
@@ -0,0 +1,148 @@
+package swift
+
+import (
+	"sort"
+
+	"github.com/bazelbuild/bazel-gazelle/label"
+	"github.com/bazelbuild/bazel-gazelle/rule"
+)
+
+// TargetBuilder accumulates the metadata gazelle discovers about a Swift
+// target (srcs, deps, visibility, module name, ...) and turns it into a
+// *rule.Rule, the way rules_python's gazelle builds up py_library/py_test
+// rules before emitting them.
+type TargetBuilder struct {
+	kind         string
+	name         string
+	pkg          string
+	moduleName   string
+	testOnly     bool
+	main         string
+	srcs         map[string]bool
+	siblingSrcs  map[string]bool
+	deps         map[string]bool
+	resolvedDeps []label.Label
+	visibility   []string
+}
+
+// NewTargetBuilder creates a TargetBuilder for a target of the given kind
+// (e.g. "swift_library") named name, declared in package pkg.
+func NewTargetBuilder(kind, name, pkg string) *TargetBuilder {
+	return &TargetBuilder{
+		kind:        kind,
+		name:        name,
+		pkg:         pkg,
+		srcs:        map[string]bool{},
+		siblingSrcs: map[string]bool{},
+		deps:        map[string]bool{},
+	}
+}
+
+// AddSrc adds src to the target's sorted set of srcs.
+func (b *TargetBuilder) AddSrc(src string) *TargetBuilder {
+	b.srcs[src] = true
+	return b
+}
+
+// AddSiblingSrc adds src to the sorted set of srcs that this target shares
+// with a sibling target (e.g. a test target's srcs that also belong to the
+// library under test).
+func (b *TargetBuilder) AddSiblingSrc(src string) *TargetBuilder {
+	b.siblingSrcs[src] = true
+	return b
+}
+
+// AddDep adds dep to the target's sorted set of deps.
+func (b *TargetBuilder) AddDep(dep string) *TargetBuilder {
+	b.deps[dep] = true
+	return b
+}
+
+// AddResolvedDep records lbl as a dependency that gazelle has already
+// resolved to a concrete Bazel label.
+func (b *TargetBuilder) AddResolvedDep(lbl label.Label) *TargetBuilder {
+	b.resolvedDeps = append(b.resolvedDeps, lbl)
+	return b
+}
+
+// SetMain sets the srcs entry that is this target's main entry point, for
+// swift_binary targets.
+func (b *TargetBuilder) SetMain(main string) *TargetBuilder {
+	b.main = main
+	return b
+}
+
+// SetModuleName sets the Swift module name the target should compile under.
+func (b *TargetBuilder) SetModuleName(moduleName string) *TargetBuilder {
+	b.moduleName = moduleName
+	return b
+}
+
+// SetTestOnly marks the target testonly.
+func (b *TargetBuilder) SetTestOnly(testOnly bool) *TargetBuilder {
+	b.testOnly = testOnly
+	return b
+}
+
+// SetVisibility sets the target's visibility.
+func (b *TargetBuilder) SetVisibility(visibility ...string) *TargetBuilder {
+	b.visibility = visibility
+	return b
+}
+
+// Label returns the Bazel label synthesized from the builder's pkg and name,
+// using the same root-level collision handling as BazelLabelFromTarget. Its
+// target name can differ from the name Build gives the rule, which is the
+// builder's name as given.
+func (b *TargetBuilder) Label(repoName string) *label.Label {
+	lbl := label.New(repoName, "", bazelLabelName(b.pkg, b.name))
+	return &lbl
+}
+
+// Build constructs the *rule.Rule described by the builder.
+func (b *TargetBuilder) Build() *rule.Rule {
+	r := rule.NewRule(b.kind, b.name)
+
+	srcs := sortedKeys(b.srcs)
+	for src := range b.siblingSrcs {
+		if !b.srcs[src] {
+			srcs = append(srcs, src)
+		}
+	}
+	sort.Strings(srcs)
+	if len(srcs) > 0 {
+		r.SetAttr("srcs", srcs)
+	}
+
+	deps := sortedKeys(b.deps)
+	for _, d := range b.resolvedDeps {
+		deps = append(deps, d.String())
+	}
+	sort.Strings(deps)
+	if len(deps) > 0 {
+		r.SetAttr("deps", deps)
+	}
+	if b.main != "" {
+		r.SetAttr("main", b.main)
+	}
+	if b.moduleName != "" {
+		r.SetAttr("module_name", b.moduleName)
+	}
+	if b.testOnly {
+		r.SetAttr("testonly", true)
+	}
+	if len(b.visibility) > 0 {
+		r.SetAttr("visibility", b.visibility)
+	}
+	return r
+}
+
+// sortedKeys returns the keys of set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package swift
+
+import (
+	"testing"
+
+	"github.com/bazelbuild/bazel-gazelle/label"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetBuilder_Build(t *testing.T) {
+	r := NewTargetBuilder("swift_library", "Foo", "Sources/Foo").
+		AddSrc("Foo.swift").
+		AddSrc("Bar.swift").
+		AddSiblingSrc("Shared.swift").
+		AddDep("//Sources/Baz:Baz").
+		SetModuleName("Foo").
+		SetTestOnly(true).
+		SetVisibility("//visibility:public").
+		Build()
+
+	assert.Equal(t, "swift_library", r.Kind())
+	assert.Equal(t, "Foo", r.Name())
+	assert.Equal(t, []string{"Bar.swift", "Foo.swift", "Shared.swift"}, r.AttrStrings("srcs"))
+	assert.Equal(t, []string{"//Sources/Baz:Baz"}, r.AttrStrings("deps"))
+	assert.Equal(t, "Foo", r.AttrString("module_name"))
+	assert.True(t, r.AttrBool("testonly"))
+	assert.Equal(t, []string{"//visibility:public"}, r.AttrStrings("visibility"))
+}
+
+func TestTargetBuilder_BuildWithResolvedDeps(t *testing.T) {
+	lbl := label.New("other_repo", "Sources/Baz", "Baz")
+	r := NewTargetBuilder("swift_library", "Foo", "Sources/Foo").
+		AddResolvedDep(lbl).
+		Build()
+
+	assert.Equal(t, []string{"@other_repo//Sources/Baz"}, r.AttrStrings("deps"))
+}
+
+func TestTargetBuilder_BuildWithMixedDeps(t *testing.T) {
+	lbl := label.New("other_repo", "Sources/Baz", "Baz")
+	r := NewTargetBuilder("swift_library", "Foo", "Sources/Foo").
+		AddDep("//Sources/Qux:Qux").
+		AddResolvedDep(lbl).
+		Build()
+
+	assert.Equal(t, []string{"//Sources/Qux:Qux", "@other_repo//Sources/Baz"}, r.AttrStrings("deps"))
+}
+
+func TestTargetBuilder_SetMain(t *testing.T) {
+	r := NewTargetBuilder("swift_binary", "Foo", "Sources/Foo").
+		AddSrc("main.swift").
+		SetMain("main.swift").
+		Build()
+
+	assert.Equal(t, "main.swift", r.AttrString("main"))
+}
+
+func TestTargetBuilder_Label(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      string
+		target   string
+		expected label.Label
+	}{
+		{
+			name:     "nested target",
+			pkg:      "Sources/Foo",
+			target:   "Foo",
+			expected: label.New("", "", "Sources_Foo"),
+		},
+		{
+			name:     "root-level target",
+			pkg:      "Foo",
+			target:   "Foo",
+			expected: label.New("", "", "Foo_Foo"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewTargetBuilder("swift_library", tt.target, tt.pkg)
+			assert.Equal(t, tt.expected, *b.Label(""))
+		})
+	}
+}